@@ -0,0 +1,101 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VectorConfigCheckPhase is the lifecycle phase of a VectorConfigCheck.
+type VectorConfigCheckPhase string
+
+const (
+	VectorConfigCheckPhasePending   VectorConfigCheckPhase = "Pending"
+	VectorConfigCheckPhaseRunning   VectorConfigCheckPhase = "Running"
+	VectorConfigCheckPhaseSucceeded VectorConfigCheckPhase = "Succeeded"
+	VectorConfigCheckPhaseFailed    VectorConfigCheckPhase = "Failed"
+)
+
+// VectorConfigCheckSpec describes a single Vector config validation run.
+// configcheck.EnsureVectorConfigCheck creates one with a controller reference to
+// the object that requested it.
+type VectorConfigCheckSpec struct {
+	// ConfigSecretRef points at the Secret holding the rendered Vector config to validate.
+	ConfigSecretRef corev1.LocalObjectReference `json:"configSecretRef"`
+
+	// Image is the Vector image used to run `vector validate`.
+	Image string `json:"image"`
+
+	// Envs are extra environment variables passed to the validation Pod.
+	// +optional
+	Envs []corev1.EnvVar `json:"envs,omitempty"`
+
+	// TimeoutSeconds bounds how long the validation Pod is given to reach a
+	// terminal phase. Past it the Pod is discarded and the check retried: the phase
+	// stays Pending and the timeout is recorded in status.error. Zero means 300s.
+	// +optional
+	TimeoutSeconds int64 `json:"timeoutSeconds,omitempty"`
+}
+
+// VectorConfigCheckStatus reports the outcome of a validation run.
+type VectorConfigCheckStatus struct {
+	// Phase is the current lifecycle phase of the check.
+	// +optional
+	Phase VectorConfigCheckPhase `json:"phase,omitempty"`
+
+	// Hash is the deterministic content digest of the validated config, used to
+	// dedupe identical configs and to name the child Pod/Secret.
+	// +optional
+	Hash string `json:"hash,omitempty"`
+
+	// Error holds the validation failure reason when Phase is Failed.
+	// +optional
+	Error string `json:"error,omitempty"`
+
+	// ObservedGeneration is the .metadata.generation last reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Hash",type=string,JSONPath=`.status.hash`
+
+// VectorConfigCheck runs a single Vector config validation and reports its result,
+// so requesting reconcilers no longer block on the validation Pod themselves.
+type VectorConfigCheck struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VectorConfigCheckSpec   `json:"spec,omitempty"`
+	Status VectorConfigCheckStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VectorConfigCheckList contains a list of VectorConfigCheck.
+type VectorConfigCheckList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VectorConfigCheck `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VectorConfigCheck{}, &VectorConfigCheckList{})
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kaasops/vector-operator/controllers/factory/config/configcheck"
+	"github.com/kaasops/vector-operator/internal/utils/k8s"
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// helmReleaseSecretType is the Secret .type Helm stamps its release storage objects
+// with; these never need to be reconciled by this operator.
+const helmReleaseSecretType = "helm.sh/release.v1"
+
+// repeatedFlag implements flag.Value for a flag that can be passed more than once,
+// e.g. `--cache-selector Pod=foo=bar --cache-selector Secret=baz=qux`.
+type repeatedFlag struct {
+	values []string
+}
+
+func (f *repeatedFlag) String() string { return strings.Join(f.values, ",") }
+func (f *repeatedFlag) Set(v string) error {
+	f.values = append(f.values, v)
+	return nil
+}
+
+// cacheObjectByKind maps the Kind half of a --cache-selector flag to the typed
+// object setupCustomCache already builds a ByObject entry for.
+var cacheObjectByKind = map[string]client.Object{
+	"Pod":            &corev1.Pod{},
+	"DaemonSet":      &appsv1.DaemonSet{},
+	"Service":        &corev1.Service{},
+	"Secret":         &corev1.Secret{},
+	"ServiceAccount": &corev1.ServiceAccount{},
+}
+
+// parseCacheSelectors turns repeated `<Kind>=<label-selector>` flag values into
+// per-Kind label requirements, so operators can narrow the cache further for a
+// specific cluster without a rebuild. Keyed by Kind: setupCustomCache merges them
+// into its per-Kind entries and only then keys its ByObject map by the objects in
+// cacheObjectByKind.
+func parseCacheSelectors(values []string) (map[string]labels.Selector, error) {
+	out := make(map[string]labels.Selector, len(values))
+	for _, v := range values {
+		kind, rawSelector, ok := strings.Cut(v, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --cache-selector %q: expected <Kind>=<label-selector>", v)
+		}
+		if _, ok := cacheObjectByKind[kind]; !ok {
+			return nil, fmt.Errorf("invalid --cache-selector %q: unknown kind %q", v, kind)
+		}
+		sel, err := labels.Parse(rawSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --cache-selector %q: %w", v, err)
+		}
+		out[kind] = sel
+	}
+	return out, nil
+}
+
+// excludeHiddenSecrets layers the VectorExcludeLabel exclusion on top of the base
+// selector, so the main cache never has to hold every ephemeral ConfigCheck Secret
+// this operator creates (Helm release Secrets are filtered separately, by .type).
+func excludeHiddenSecrets(base labels.Selector) (labels.Selector, error) {
+	notExcluded, err := labels.NewRequirement(k8s.VectorExcludeLabel, selection.NotEquals, []string{"true"})
+	if err != nil {
+		return nil, err
+	}
+	return base.Add(*notExcluded), nil
+}
+
+// setupCustomCache narrows the manager's cache by namespace/label and, for Secrets,
+// additionally excludes Helm release storage and ConfigCheck's own ephemeral
+// Secrets so this operator isn't forced to hold events for objects it will never
+// reconcile. extraSelectors (from --cache-selector) are ANDed on top per Kind.
+func setupCustomCache(mgrOptions *ctrl.Options, namespace, watchLabel string, extraSelectors map[string]labels.Selector) (*ctrl.Options, error) {
+	ns := namespace
+	if ns == "" {
+		ns = cache.AllNamespaces
+	}
+
+	var labelSelector labels.Selector
+	if watchLabel != "" {
+		labelSelector = labels.Set{k8s.ManagedByLabelKey: "vector-operator", k8s.NameLabelKey: watchLabel}.AsSelector()
+	} else {
+		labelSelector = labels.Everything()
+	}
+
+	secretSelector, err := excludeHiddenSecrets(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	byObjectByKind := map[string]cache.ByObject{
+		"Pod":            {Namespaces: map[string]cache.Config{ns: {LabelSelector: labelSelector}}},
+		"DaemonSet":      {Namespaces: map[string]cache.Config{ns: {LabelSelector: labelSelector}}},
+		"Service":        {Namespaces: map[string]cache.Config{ns: {LabelSelector: labelSelector}}},
+		"ServiceAccount": {Namespaces: map[string]cache.Config{ns: {LabelSelector: labelSelector}}},
+		"Secret": {
+			Namespaces: map[string]cache.Config{ns: {
+				LabelSelector: secretSelector,
+				FieldSelector: fields.OneTermNotEqualSelector("type", helmReleaseSecretType),
+			}},
+		},
+	}
+
+	for kind, sel := range extraSelectors {
+		byObj, ok := byObjectByKind[kind]
+		if !ok {
+			return nil, fmt.Errorf("--cache-selector given for a kind with no base cache entry")
+		}
+		nsCfg := byObj.Namespaces[ns]
+		reqs, selectable := sel.Requirements()
+		if !selectable {
+			return nil, fmt.Errorf("--cache-selector selector is not selectable")
+		}
+		nsCfg.LabelSelector = nsCfg.LabelSelector.Add(reqs...)
+		byObj.Namespaces[ns] = nsCfg
+		byObjectByKind[kind] = byObj
+	}
+
+	byObject := make(map[client.Object]cache.ByObject, len(byObjectByKind))
+	for kind, byObj := range byObjectByKind {
+		byObject[cacheObjectByKind[kind]] = byObj
+	}
+
+	mgrOptions.Cache = cache.Options{ByObject: byObject}
+
+	return mgrOptions, nil
+}
+
+// newConfigCheckCache builds a cache scoped to just the ephemeral ConfigCheck
+// Pods/Secrets (configcheck.LabelsForVectorConfigCheck()) for configcheck's own reads.
+// The main cache excludes ConfigCheck Secrets but still holds ConfigCheck Pods
+// (unless --watch-name filters them out). Callers must mgr.Add(..) it and
+// build a client.Client with Cache.Reader pointed at it for configcheck's own Get
+// and List calls.
+func newConfigCheckCache(mgr ctrl.Manager, namespace string) (cache.Cache, error) {
+	ns := namespace
+	if ns == "" {
+		ns = cache.AllNamespaces
+	}
+
+	sel := labels.SelectorFromSet(configcheck.LabelsForVectorConfigCheck())
+
+	return cache.New(mgr.GetConfig(), cache.Options{
+		Scheme: mgr.GetScheme(),
+		Mapper: mgr.GetRESTMapper(),
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.Pod{}:    {Namespaces: map[string]cache.Config{ns: {LabelSelector: sel}}},
+			&corev1.Secret{}: {Namespaces: map[string]cache.Config{ns: {LabelSelector: sel}}},
+		},
+	})
+}
+
+// cacheObjectsGauge reports, per watched GVK, how many objects the manager's
+// caches currently hold, so operators can verify --cache-selector/--watch-label
+// narrowing actually reduced what's being held in memory.
+var cacheObjectsGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "vector_operator_cache_objects",
+	Help: "Number of objects of a given GVK currently held in the operator's caches.",
+}, []string{"gvk"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(cacheObjectsGauge)
+}
+
+// cacheListing pairs a human label with a lister function so pollCacheMetrics can
+// stay agnostic of which cache (main or ConfigCheck-dedicated) each kind lives in.
+type cacheListing struct {
+	gvk  string
+	list func(ctx context.Context) (int, error)
+}
+
+// cacheListingsFor builds the cacheListing set pollCacheMetrics reports on: every
+// kind the main manager cache holds, plus the dedicated ConfigCheck cache's Pods and
+// Secrets, so both caches' sizes are visible even though they're never queried
+// together by any reconciler.
+func cacheListingsFor(mgr ctrl.Manager, configCheckCache cache.Cache, namespace string) []cacheListing {
+	opts := []client.ListOption{}
+	if namespace != "" {
+		opts = append(opts, client.InNamespace(namespace))
+	}
+
+	listing := func(gvk string, c client.Reader, list client.ObjectList) cacheListing {
+		return cacheListing{
+			gvk: gvk,
+			list: func(ctx context.Context) (int, error) {
+				if err := c.List(ctx, list, opts...); err != nil {
+					return 0, err
+				}
+				return meta.LenList(list)
+			},
+		}
+	}
+
+	mgrCache := mgr.GetCache()
+	return []cacheListing{
+		listing("Pod", mgrCache, &corev1.PodList{}),
+		listing("DaemonSet", mgrCache, &appsv1.DaemonSetList{}),
+		listing("Service", mgrCache, &corev1.ServiceList{}),
+		listing("Secret", mgrCache, &corev1.SecretList{}),
+		listing("ServiceAccount", mgrCache, &corev1.ServiceAccountList{}),
+		listing("ConfigCheckPod", configCheckCache, &corev1.PodList{}),
+		listing("ConfigCheckSecret", configCheckCache, &corev1.SecretList{}),
+	}
+}
+
+// pollCacheMetrics periodically refreshes cacheObjectsGauge until ctx is canceled.
+func pollCacheMetrics(ctx context.Context, interval time.Duration, listings []cacheListing) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, l := range listings {
+				n, err := l.list(ctx)
+				if err != nil {
+					setupLog.Error(err, "unable to list objects for cache metrics", "gvk", l.gvk)
+					continue
+				}
+				cacheObjectsGauge.WithLabelValues(l.gvk).Set(float64(n))
+			}
+		}
+	}
+}
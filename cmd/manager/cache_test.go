@@ -0,0 +1,98 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/kaasops/vector-operator/internal/utils/k8s"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestParseCacheSelectors(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		wantErr bool
+	}{
+		{
+			name:   "single valid selector",
+			values: []string{"Pod=app=vector"},
+		},
+		{
+			name:   "multiple valid selectors",
+			values: []string{"Pod=app=vector", "Secret=app!=helm"},
+		},
+		{
+			name:    "missing equals",
+			values:  []string{"Pod"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown kind",
+			values:  []string{"Ingress=app=vector"},
+			wantErr: true,
+		},
+		{
+			name:    "unparseable label selector",
+			values:  []string{"Pod=!!!not a selector"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := parseCacheSelectors(tt.values)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseCacheSelectors(%v) = nil error, want error", tt.values)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseCacheSelectors(%v) unexpected error: %v", tt.values, err)
+			}
+			if len(out) != len(tt.values) {
+				t.Errorf("parseCacheSelectors(%v) = %d entries, want %d", tt.values, len(out), len(tt.values))
+			}
+		})
+	}
+}
+
+func TestParseCacheSelectorsMapsKindToSelector(t *testing.T) {
+	out, err := parseCacheSelectors([]string{"Pod=app=vector"})
+	if err != nil {
+		t.Fatalf("parseCacheSelectors() unexpected error: %v", err)
+	}
+
+	sel, ok := out["Pod"]
+	if !ok {
+		t.Fatalf("parseCacheSelectors() has no entry for %q: %v", "Pod", out)
+	}
+	if !sel.Matches(labels.Set{"app": "vector"}) {
+		t.Errorf("selector %v does not match app=vector", sel)
+	}
+}
+
+func TestExcludeHiddenSecrets(t *testing.T) {
+	base := labels.Everything()
+
+	sel, err := excludeHiddenSecrets(base)
+	if err != nil {
+		t.Fatalf("excludeHiddenSecrets() unexpected error: %v", err)
+	}
+
+	if sel.Matches(labels.Set{k8s.VectorExcludeLabel: "true"}) {
+		t.Errorf("excludeHiddenSecrets() selector still matches a hidden Secret's labels")
+	}
+	if !sel.Matches(labels.Set{"app": "vector"}) {
+		t.Errorf("excludeHiddenSecrets() selector rejects an ordinary Secret's labels")
+	}
+}
+
+func TestCacheObjectByKindValuesAreClientObjects(t *testing.T) {
+	for kind := range cacheObjectByKind {
+		if _, ok := cacheObjectByKind[kind].(client.Object); !ok {
+			t.Errorf("cacheObjectByKind[%q] does not implement client.Object", kind)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	cmv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// injectCAFromAnnotation is the cert-manager annotation that CA-injector watches for
+// on a ValidatingWebhookConfiguration / CRD conversion webhook, keyed off the
+// Certificate this package reconciles. ensureCAInjectionAnnotation below applies it
+// directly via the API, since nothing else in this operator's manifests does.
+const injectCAFromAnnotation = "cert-manager.io/inject-ca-from"
+
+// certIssuerOptions configures a single cert-manager.io/v1 Certificate (webhook or
+// metrics serving cert). Empty Issuer means cert-manager integration is disabled and
+// the caller falls back to controller-runtime's self-signed certs.
+type certIssuerOptions struct {
+	Issuer     string
+	IssuerKind string
+	Secret     string
+}
+
+func (o certIssuerOptions) enabled() bool {
+	return o.Issuer != "" && o.Secret != ""
+}
+
+// ensureCertificate creates or updates the Certificate backing name's TLS Secret,
+// with DNS SANs for the given Service, so webhook/metrics TLS is cert-manager-issued
+// instead of self-signed.
+func ensureCertificate(ctx context.Context, c client.Client, name, namespace, serviceName string, opts certIssuerOptions) error {
+	if !opts.enabled() {
+		return nil
+	}
+
+	issuerKind := opts.IssuerKind
+	if issuerKind == "" {
+		issuerKind = "Issuer"
+	}
+
+	cert := &cmv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, c, cert, func() error {
+		cert.Spec = cmv1.CertificateSpec{
+			SecretName: opts.Secret,
+			DNSNames: []string{
+				fmt.Sprintf("%s.%s.svc", serviceName, namespace),
+				fmt.Sprintf("%s.%s.svc.cluster.local", serviceName, namespace),
+			},
+			IssuerRef: cmmeta.ObjectReference{
+				Name: opts.Issuer,
+				Kind: issuerKind,
+			},
+		}
+		return nil
+	})
+	return err
+}
+
+// ensureCAInjectionAnnotation patches webhookConfigName's injectCAFromAnnotation to
+// point at the given Certificate, so cert-manager's CA injector populates the
+// webhook's CA bundle automatically instead of it being left empty until something
+// else annotates the (pre-existing, manifest-installed) ValidatingWebhookConfiguration.
+// A missing webhook configuration is not an error: the webhook may not be installed
+// in this cluster at all (e.g. a dev deployment without the webhook manifests).
+func ensureCAInjectionAnnotation(ctx context.Context, c client.Client, webhookConfigName, certNamespace, certName string) error {
+	webhookConfig := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	if err := c.Get(ctx, client.ObjectKey{Name: webhookConfigName}, webhookConfig); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	patch := client.MergeFrom(webhookConfig.DeepCopy())
+	if webhookConfig.Annotations == nil {
+		webhookConfig.Annotations = map[string]string{}
+	}
+	webhookConfig.Annotations[injectCAFromAnnotation] = fmt.Sprintf("%s/%s", certNamespace, certName)
+
+	return c.Patch(ctx, webhookConfig, patch)
+}
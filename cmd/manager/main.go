@@ -25,18 +25,18 @@ import (
 	"os"
 	"time"
 
+	"github.com/spf13/pflag"
+
+	cmv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
 	monitorv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
-	appsv1 "k8s.io/api/apps/v1"
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
-	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"k8s.io/component-base/featuregate"
+	logsapi "k8s.io/component-base/logs/api/v1"
+	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 
-	"github.com/kaasops/vector-operator/internal/utils/k8s"
-
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
@@ -46,7 +46,6 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
-	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -66,6 +65,7 @@ func init() {
 
 	utilruntime.Must(v1alpha1.AddToScheme(scheme))
 	utilruntime.Must(monitorv1.AddToScheme(scheme))
+	utilruntime.Must(cmv1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -79,6 +79,15 @@ func main() {
 	var watchNamespace string
 	var watchLabel string
 	var configCheckTimeout time.Duration
+	var configCheckCacheTTL time.Duration
+	var webhookCertIssuer string
+	var webhookCertIssuerKind string
+	var webhookCertSecret string
+	var webhookConfigName string
+	var metricsCertIssuer string
+	var metricsCertIssuerKind string
+	var metricsCertSecret string
+	var cacheSelectors repeatedFlag
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -93,13 +102,39 @@ func main() {
 	flag.StringVar(&watchNamespace, "watch-namespace", "", "Namespace to filter the list of watched objects")
 	flag.StringVar(&watchLabel, "watch-name", "", "Filter the list of watched objects by checking the app.kubernetes.io/managed-by label")
 	flag.DurationVar(&configCheckTimeout, "configcheck-timeout", 300*time.Second, "configcheck timeout")
-	opts := zap.Options{
-		Development: true,
+	flag.DurationVar(&configCheckCacheTTL, "configcheck-cache-ttl", 0, "how long a Succeeded ConfigCheck Pod is trusted as a cache hit for an "+
+		"identical config before it is re-validated; 0 means cache hits never expire")
+	flag.StringVar(&webhookCertIssuer, "webhook-cert-issuer", "", "cert-manager Issuer/ClusterIssuer name used to issue the webhook serving cert. "+
+		"Leave empty to keep using controller-runtime's self-signed cert.")
+	flag.StringVar(&webhookCertIssuerKind, "webhook-cert-issuer-kind", "Issuer", "Kind of --webhook-cert-issuer: Issuer or ClusterIssuer")
+	flag.StringVar(&webhookCertSecret, "webhook-cert-secret", "webhook-server-cert", "Secret cert-manager writes the webhook serving cert into")
+	flag.StringVar(&webhookConfigName, "webhook-configuration-name", "vector-operator-validating-webhook-configuration",
+		"Name of the ValidatingWebhookConfiguration to annotate with inject-ca-from when --webhook-cert-issuer is set")
+	flag.StringVar(&metricsCertIssuer, "metrics-cert-issuer", "", "cert-manager Issuer/ClusterIssuer name used to issue the metrics serving cert. "+
+		"Leave empty to keep using controller-runtime's self-signed cert.")
+	flag.StringVar(&metricsCertIssuerKind, "metrics-cert-issuer-kind", "Issuer", "Kind of --metrics-cert-issuer: Issuer or ClusterIssuer")
+	flag.StringVar(&metricsCertSecret, "metrics-cert-secret", "metrics-server-cert", "Secret cert-manager writes the metrics serving cert into")
+	flag.Var(&cacheSelectors, "cache-selector", "repeatable <Kind>=<label-selector> narrowing the manager cache for a "+
+		"specific watched kind (Pod, DaemonSet, Service, Secret, ServiceAccount); ANDed with --watch-name")
+
+	featureGate := featuregate.NewFeatureGate()
+	utilruntime.Must(logsapi.AddFeatureGates(featureGate))
+
+	loggingConfig := logsapi.NewLoggingConfiguration()
+	pfs := pflag.NewFlagSet("vector-operator", pflag.ExitOnError)
+	logsapi.AddFlags(loggingConfig, pfs)
+	pfs.AddGoFlagSet(flag.CommandLine)
+	if err := pfs.Parse(os.Args[1:]); err != nil {
+		setupLog.Error(err, "unable to parse flags")
+		os.Exit(1)
+	}
+
+	if err := logsapi.ValidateAndApply(loggingConfig, featureGate); err != nil {
+		setupLog.Error(err, "invalid logging configuration")
+		os.Exit(1)
 	}
-	opts.BindFlags(flag.CommandLine)
-	flag.Parse()
 
-	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	ctrl.SetLogger(klog.Background())
 	setupLog.Info("build info", "version", buildinfo.Version)
 
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
@@ -159,6 +194,43 @@ func main() {
 		os.Exit(1)
 	}
 
+	operatorNamespace := os.Getenv("POD_NAMESPACE")
+	webhookCertOpts := certIssuerOptions{Issuer: webhookCertIssuer, IssuerKind: webhookCertIssuerKind, Secret: webhookCertSecret}
+	metricsCertOpts := certIssuerOptions{Issuer: metricsCertIssuer, IssuerKind: metricsCertIssuerKind, Secret: metricsCertSecret}
+	if webhookCertOpts.enabled() || metricsCertOpts.enabled() {
+		if operatorNamespace == "" {
+			setupLog.Error(fmt.Errorf("POD_NAMESPACE is required"), "cannot reconcile cert-manager Certificates without the operator's namespace")
+			os.Exit(1)
+		}
+
+		// a direct (non-cached) client: the manager's cache isn't started yet at this point.
+		bootstrapClient, err := client.New(config, client.Options{Scheme: scheme})
+		if err != nil {
+			setupLog.Error(err, "unable to create bootstrap client for cert-manager setup")
+			os.Exit(1)
+		}
+
+		if err := ensureCertificate(context.Background(), bootstrapClient, "vector-operator-webhook-cert", operatorNamespace, "vector-operator-webhook-service", webhookCertOpts); err != nil {
+			setupLog.Error(err, "unable to reconcile webhook Certificate")
+			os.Exit(1)
+		}
+		if err := ensureCertificate(context.Background(), bootstrapClient, "vector-operator-metrics-cert", operatorNamespace, "vector-operator-controller-manager-metrics-service", metricsCertOpts); err != nil {
+			setupLog.Error(err, "unable to reconcile metrics Certificate")
+			os.Exit(1)
+		}
+
+		// cert-manager writes tls.crt/tls.key into the Secret above, which the Deployment
+		// mounts at webhook.Options.CertDir / metricsserver.Options.CertDir (their
+		// default path); controller-runtime's servers already watch CertDir for
+		// rotation via their own CertWatcher, so no extra reload wiring is needed here.
+		if webhookCertOpts.enabled() {
+			if err := ensureCAInjectionAnnotation(context.Background(), bootstrapClient, webhookConfigName, operatorNamespace, "vector-operator-webhook-cert"); err != nil {
+				setupLog.Error(err, "unable to annotate ValidatingWebhookConfiguration for cert-manager CA injection")
+				os.Exit(1)
+			}
+		}
+	}
+
 	mgrOptions := ctrl.Options{
 		Scheme:                 scheme,
 		Metrics:                metricsServerOptions,
@@ -178,7 +250,13 @@ func main() {
 		// after the manager stops then its usage might be unsafe.
 		// LeaderElectionReleaseOnCancel: true,
 	}
-	customMgrOptions, err := setupCustomCache(&mgrOptions, watchNamespace, watchLabel)
+	extraCacheSelectors, err := parseCacheSelectors(cacheSelectors.values)
+	if err != nil {
+		setupLog.Error(err, "invalid --cache-selector")
+		os.Exit(1)
+	}
+
+	customMgrOptions, err := setupCustomCache(&mgrOptions, watchNamespace, watchLabel, extraCacheSelectors)
 	if err != nil {
 		setupLog.Error(err, "unable to set up custom cache settings")
 		os.Exit(1)
@@ -190,6 +268,24 @@ func main() {
 		os.Exit(1)
 	}
 
+	configCheckCache, err := newConfigCheckCache(mgr, watchNamespace)
+	if err != nil {
+		setupLog.Error(err, "unable to set up VectorConfigCheck cache")
+		os.Exit(1)
+	}
+	if err := mgr.Add(configCheckCache); err != nil {
+		setupLog.Error(err, "unable to register VectorConfigCheck cache")
+		os.Exit(1)
+	}
+	configCheckClient, err := client.New(config, client.Options{
+		Scheme: mgr.GetScheme(),
+		Cache:  &client.CacheOptions{Reader: configCheckCache},
+	})
+	if err != nil {
+		setupLog.Error(err, "unable to create VectorConfigCheck client")
+		os.Exit(1)
+	}
+
 	vectorAgentEventCh := make(chan event.GenericEvent)
 	defer close(vectorAgentEventCh)
 
@@ -252,11 +348,24 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "ClusterVectorAggregator")
 		os.Exit(1)
 	}
+
+	if err = (&controller.ConfigCheckReconciler{
+		Client:     mgr.GetClient(),
+		PodsClient: configCheckClient,
+		Clientset:  clientset,
+		CacheTTL:   configCheckCacheTTL,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "VectorConfigCheck")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 
-	go reconcileWithDelay(context.Background(), vectorAgentsPipelineEventCh, vectorAgentEventCh, time.Second*10)
-	go reconcileWithDelay(context.Background(), vectorAggregatorsPipelineEventCh, vectorAggregatorsEventCh, time.Second*10)
-	go reconcileWithDelay(context.Background(), clusterVectorAggregatorsPipelineEventCh, clusterVectorAggregatorsEventCh, time.Second*10)
+	ctx := ctrl.SetupSignalHandler()
+
+	go reconcileWithDelay(ctx, vectorAgentsPipelineEventCh, vectorAgentEventCh, time.Second*10, "vector")
+	go reconcileWithDelay(ctx, vectorAggregatorsPipelineEventCh, vectorAggregatorsEventCh, time.Second*10, "vector-aggregator")
+	go reconcileWithDelay(ctx, clusterVectorAggregatorsPipelineEventCh, clusterVectorAggregatorsEventCh, time.Second*10, "cluster-vector-aggregator")
+	go pollCacheMetrics(ctx, 30*time.Second, cacheListingsFor(mgr, configCheckCache, watchNamespace))
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
@@ -268,72 +377,20 @@ func main() {
 	}
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
 
-func setupCustomCache(mgrOptions *ctrl.Options, namespace string, watchLabel string) (*ctrl.Options, error) {
-	if namespace == "" && watchLabel == "" {
-		return mgrOptions, nil
-	}
-
-	if namespace == "" {
-		namespace = cache.AllNamespaces
-	}
-
-	var labelSelector labels.Selector
-	if watchLabel != "" {
-		labelSelector = labels.Set{k8s.ManagedByLabelKey: "vector-operator", k8s.NameLabelKey: watchLabel}.AsSelector()
-	} else {
-		labelSelector = labels.Everything()
-	}
-
-	mgrOptions.Cache = cache.Options{
-		ByObject: map[client.Object]cache.ByObject{
-			&corev1.Pod{}: {
-				Namespaces: map[string]cache.Config{
-					namespace: {
-						LabelSelector: labelSelector,
-					},
-				},
-			},
-			&appsv1.DaemonSet{}: {
-				Namespaces: map[string]cache.Config{
-					namespace: {
-						LabelSelector: labelSelector,
-					},
-				},
-			},
-			&corev1.Service{}: {
-				Namespaces: map[string]cache.Config{
-					namespace: {
-						LabelSelector: labelSelector,
-					},
-				},
-			},
-			&corev1.Secret{}: {
-				Namespaces: map[string]cache.Config{
-					namespace: {
-						LabelSelector: labelSelector,
-					},
-				},
-			},
-			&corev1.ServiceAccount{}: {
-				Namespaces: map[string]cache.Config{
-					namespace: {
-						LabelSelector: labelSelector,
-					},
-				},
-			},
-		},
-	}
-
-	return mgrOptions, nil
-}
+// reconcileWithDelay coalesces bursts of GenericEvents on in (one per object) and
+// flushes them to out once no new event has arrived for delay, so a storm of
+// upstream changes doesn't trigger a reconcile per event. name is logged as the
+// "target" value on the shared "reconcile-coalescer" sub-logger (e.g. "vector",
+// "vector-aggregator"), so each coalescer's log lines can be told apart.
+func reconcileWithDelay(ctx context.Context, in, out chan event.GenericEvent, delay time.Duration, name string) {
+	log := ctrl.Log.WithName("reconcile-coalescer").WithValues("target", name)
 
-func reconcileWithDelay(ctx context.Context, in, out chan event.GenericEvent, delay time.Duration) {
 	ticker := time.NewTicker(delay)
 	defer ticker.Stop()
 
@@ -348,9 +405,11 @@ func reconcileWithDelay(ctx context.Context, in, out chan event.GenericEvent, de
 			key := fmt.Sprintf("%s/%s", ev.Object.GetNamespace(), ev.Object.GetName())
 			if _, ok := store[key]; !ok {
 				store[key] = ev
+				log.V(1).Info("coalescing reconcile request", "key", key)
 			}
 		case <-ticker.C:
 			if len(store) != 0 {
+				log.V(1).Info("flushing coalesced reconcile requests", "count", len(store))
 				for nn, ev := range store {
 					out <- ev
 					delete(store, nn)
@@ -18,18 +18,58 @@ package configcheck
 
 import (
 	"context"
-	"math/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
 	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/kaasops/vector-operator/controllers/factory/utils/k8s"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// defaultConfigCheckTimeout is used when ConfigCheck is built without an explicit timeout.
+const defaultConfigCheckTimeout = 300 * time.Second
+
+// hashRunesLen is the length of the hex digest used in the derived Pod/Secret name.
+const hashRunesLen = 10
+
+// OwnerNameLabel is set on the validation Pod to the name of the VectorConfigCheck
+// that requested it, so a Pod watch can map a Pod event back to the owning CR
+// without the reconciler having to scan every Pod in the namespace.
+const OwnerNameLabel = "vector.kaasops.io/configcheck-name"
+
+// LastGoodHashAnnotation is the annotation key reconcilers should set on the owning
+// Vector/VectorAggregator CR with the hash of the last successfully validated config,
+// so a controller restart does not re-validate a config it already blessed.
+const LastGoodHashAnnotation = "vector.kaasops.io/last-validated-configcheck-hash"
+
+// LastGoodHash returns the value of LastGoodHashAnnotation on obj, if set.
+func LastGoodHash(obj metav1.Object) (string, bool) {
+	v, ok := obj.GetAnnotations()[LastGoodHashAnnotation]
+	return v, ok
+}
+
+// SetLastGoodHash records hash as the owning object's LastGoodHashAnnotation.
+func SetLastGoodHash(obj metav1.Object, hash string) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[LastGoodHashAnnotation] = hash
+	obj.SetAnnotations(annotations)
+}
+
 type ConfigCheck struct {
 	Config []byte
 
@@ -41,6 +81,12 @@ type ConfigCheck struct {
 	Image     string
 	Envs      []corev1.EnvVar
 	Hash      string
+	Timeout   time.Duration
+
+	// CacheTTL, when non-zero, bounds how long a Succeeded ConfigCheck Pod is trusted
+	// as a cache hit (and how long cleanup() keeps it around afterwards). Zero means
+	// cache hits never expire.
+	CacheTTL time.Duration
 }
 
 func New(
@@ -49,7 +95,12 @@ func New(
 	cs *kubernetes.Clientset,
 	name, namespace, image string,
 	envs []corev1.EnvVar,
+	timeout, cacheTTL time.Duration,
 ) *ConfigCheck {
+	if timeout == 0 {
+		timeout = defaultConfigCheckTimeout
+	}
+
 	return &ConfigCheck{
 		Config:    config,
 		Client:    c,
@@ -58,6 +109,9 @@ func New(
 		Namespace: namespace,
 		Image:     image,
 		Envs:      envs,
+		Timeout:   timeout,
+		CacheTTL:  cacheTTL,
+		Hash:      (&ConfigCheck{Config: config, Image: image, Envs: envs}).computeHash(),
 	}
 }
 
@@ -66,21 +120,123 @@ func (cc *ConfigCheck) Run(ctx context.Context) error {
 
 	log.Info("start ConfigCheck")
 
-	if err := cc.ensureVectorConfigCheckRBAC(ctx); err != nil {
+	ctx, cancel := context.WithTimeout(ctx, cc.Timeout)
+	defer cancel()
+
+	pod, cached, err := cc.resolvePod(ctx, log)
+	if err != nil {
+		return err
+	}
+	if cached {
+		return nil
+	}
+
+	if err := cc.getCheckResult(ctx, pod); err != nil {
 		return err
 	}
 
-	cc.Hash = randStringRunes()
+	return cc.cleanup(ctx)
+}
+
+// EnsurePod creates or reuses the validation Pod for the current config and returns
+// it immediately, without blocking for a terminal phase the way Run does. Callers
+// such as ConfigCheckReconciler observe the returned Pod's own Status.Phase (e.g.
+// from a watch) across separate, non-blocking reconciles instead of dedicating a
+// worker to the watch loop in getCheckResult. cc.Hash is populated as a side effect.
+func (cc *ConfigCheck) EnsurePod(ctx context.Context) (*corev1.Pod, error) {
+	log := log.FromContext(ctx).WithValues("Vector ConfigCheck", cc.Name)
+	pod, _, err := cc.resolvePod(ctx, log)
+	return pod, err
+}
+
+// resolvePod looks for a ConfigCheck Pod already running under the deterministic
+// name derived from cc.Hash and reports whether it is already a usable cache hit. A
+// Succeeded Pod within CacheTTL is a cache hit. A Failed Pod is never reused: since
+// cc.Hash is a deterministic content digest, leaving it in place would permanently
+// replay the same validation failure for that config, so it is deleted and replaced
+// by a fresh Pod. A Pending/Running Pod, or a freshly created one, is returned as
+// still in-flight.
+func (cc *ConfigCheck) resolvePod(ctx context.Context, log logr.Logger) (*corev1.Pod, bool, error) {
+	if err := cc.ensureVectorConfigCheckRBAC(ctx); err != nil {
+		return nil, false, err
+	}
+
+	cc.Hash = cc.computeHash()
+
+	existing, err := k8s.GetPod(ctx, types.NamespacedName{Name: cc.getNameVectorConfigCheck(), Namespace: cc.Namespace}, cc.Client)
+	if apierrors.IsNotFound(err) {
+		pod, err := cc.createCheckPod(ctx)
+		return pod, false, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch existing.Status.Phase {
+	case corev1.PodSucceeded:
+		if cc.CacheTTL == 0 || cc.withinCacheTTL(existing) {
+			log.Info("reusing cached ConfigCheck result", "hash", cc.Hash)
+			return existing, true, nil
+		}
+
+		log.Info("cached ConfigCheck result expired, re-validating", "hash", cc.Hash)
+		if err := cc.deleteInFlight(ctx, existing); err != nil {
+			return nil, false, err
+		}
+		pod, err := cc.createCheckPod(ctx)
+		return pod, false, err
+	case corev1.PodFailed:
+		log.Info("stale failed ConfigCheck Pod found, re-validating", "hash", cc.Hash)
+		if err := cc.deleteInFlight(ctx, existing); err != nil {
+			return nil, false, err
+		}
+		pod, err := cc.createCheckPod(ctx)
+		return pod, false, err
+	default:
+		log.Info("attaching to in-flight ConfigCheck Pod", "hash", cc.Hash, "phase", existing.Status.Phase)
+		return existing, false, nil
+	}
+}
 
+// createCheckPod renders the config Secret and starts a fresh validation Pod,
+// labelled with OwnerNameLabel so a non-blocking caller's Pod watch can map it back
+// to this ConfigCheck's owning CR.
+func (cc *ConfigCheck) createCheckPod(ctx context.Context) (*corev1.Pod, error) {
 	if err := cc.ensureVectorConfigCheckConfig(ctx); err != nil {
-		return err
+		return nil, err
 	}
 
-	if err := cc.checkVectorConfigCheckPod(ctx); err != nil {
-		return err
+	pod := cc.createVectorConfigCheckPod()
+	if pod.Labels == nil {
+		pod.Labels = map[string]string{}
 	}
+	pod.Labels[OwnerNameLabel] = cc.Name
 
-	return nil
+	if err := k8s.CreatePod(ctx, pod, cc.Client); err != nil {
+		return nil, err
+	}
+	return pod, nil
+}
+
+// Abandon deletes the validation Pod (and its config Secret) before it reached a
+// terminal phase, e.g. because a non-blocking caller decided it has run past its
+// own timeout. The next EnsurePod call creates a fresh one.
+func (cc *ConfigCheck) Abandon(ctx context.Context, pod *corev1.Pod) error {
+	return cc.deleteInFlight(ctx, pod)
+}
+
+// Cleanup garbage-collects other ConfigCheck Pods/Secrets in the namespace that are
+// Succeeded and past CacheTTL. Run already does this itself; non-blocking callers
+// should call it once a check of theirs reaches a terminal phase.
+func (cc *ConfigCheck) Cleanup(ctx context.Context) error {
+	return cc.cleanup(ctx)
+}
+
+func (cc *ConfigCheck) withinCacheTTL(pod *corev1.Pod) bool {
+	if pod.Status.StartTime == nil {
+		return true
+	}
+	return time.Since(pod.Status.StartTime.Time) < cc.CacheTTL
 }
 
 func (cc *ConfigCheck) ensureVectorConfigCheckRBAC(ctx context.Context) error {
@@ -101,27 +257,7 @@ func (cc *ConfigCheck) ensureVectorConfigCheckConfig(ctx context.Context) error
 	return k8s.CreateOrUpdateResource(ctx, vectorConfigCheckSecret, cc.Client)
 }
 
-func (cc *ConfigCheck) checkVectorConfigCheckPod(ctx context.Context) error {
-	vectorConfigCheckPod := cc.createVectorConfigCheckPod()
-
-	err := k8s.CreatePod(ctx, vectorConfigCheckPod, cc.Client)
-	if err != nil {
-		return err
-	}
-
-	err = cc.getCheckResult(ctx, vectorConfigCheckPod)
-	if err != nil {
-		return err
-	}
-
-	err = cc.cleanup(ctx)
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
-func labelsForVectorConfigCheck() map[string]string {
+func LabelsForVectorConfigCheck() map[string]string {
 	return map[string]string{
 		k8s.ManagedByLabelKey:  "vector-operator",
 		k8s.NameLabelKey:       "vector-configcheck",
@@ -136,40 +272,133 @@ func (cc *ConfigCheck) getNameVectorConfigCheck() string {
 	return n
 }
 
-func randStringRunes() string {
-	var letterRunes = []rune("abcdefghijklmnopqrstuvwxyz")
-
-	b := make([]rune, 5)
-	for i := range b {
-		b[i] = letterRunes[rand.Intn(len(letterRunes))]
+// computeHash derives a deterministic content digest of the config, image and envs,
+// so identical Vector configurations resolve to the same Pod/Secret name and can
+// share a cached validation result instead of spinning up a new Pod every reconcile.
+func (cc *ConfigCheck) computeHash() string {
+	envs := make([]corev1.EnvVar, len(cc.Envs))
+	copy(envs, cc.Envs)
+	sort.Slice(envs, func(i, j int) bool { return envs[i].Name < envs[j].Name })
+
+	h := sha256.New()
+	h.Write(cc.Config)
+	h.Write([]byte(cc.Image))
+	for _, e := range envs {
+		h.Write([]byte(e.Name))
+		h.Write([]byte(e.Value))
 	}
-	return string(b)
+
+	sum := hex.EncodeToString(h.Sum(nil))
+	return sum[:hashRunesLen]
 }
 
+// getCheckResult watches the validation Pod until it reaches a terminal phase,
+// ctx is canceled, or cc.Timeout elapses. It no longer polls on a fixed interval.
 func (cc *ConfigCheck) getCheckResult(ctx context.Context, pod *corev1.Pod) error {
 	log := log.FromContext(ctx).WithValues("Vector ConfigCheck", pod.Name)
 
+	// the Pod may already be terminal by the time we start watching it
+	existing, err := k8s.GetPod(ctx, client.ObjectKeyFromObject(pod), cc.Client)
+	if err != nil {
+		return err
+	}
+	if done, err := cc.evaluateCheckPodPhase(ctx, existing, log); done {
+		return err
+	}
+
+	watcher, err := cc.ClientSet.CoreV1().Pods(pod.Namespace).Watch(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", pod.Name).String(),
+	})
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+
+	// onDone removes the in-flight Pod/Secret once ctx is done and returns a
+	// *TimeoutError only when ctx ran out of time, ctx.Err() otherwise.
+	onDone := func() error {
+		if err := cc.deleteInFlight(context.Background(), pod); err != nil {
+			log.Error(err, "failed to clean up ConfigCheck Pod after cancellation")
+		}
+		if ctx.Err() == context.DeadlineExceeded {
+			return newTimeoutError(cc.Timeout)
+		}
+		return ctx.Err()
+	}
+
 	for {
-		existing, err := k8s.GetPod(ctx, client.ObjectKeyFromObject(pod), cc.Client)
+		select {
+		case <-ctx.Done():
+			return onDone()
+		case ev, ok := <-watcher.ResultChan():
+			if !ok {
+				// the watch is closed when ctx is done, possibly before ctx.Done() is selected
+				if ctx.Err() != nil {
+					return onDone()
+				}
+				return fmt.Errorf("ConfigCheck Pod watch for %s closed unexpectedly", pod.Name)
+			}
+			switch ev.Type {
+			case watch.Added, watch.Modified:
+				watchedPod, ok := ev.Object.(*corev1.Pod)
+				if !ok {
+					continue
+				}
+				log.Info("wait Validate Vector Config Result", "phase", watchedPod.Status.Phase)
+				if done, err := cc.evaluateCheckPodPhase(ctx, watchedPod, log); done {
+					return err
+				}
+			case watch.Deleted:
+				return fmt.Errorf("ConfigCheck Pod %s was deleted before validation completed", pod.Name)
+			case watch.Error:
+				if status, ok := ev.Object.(*metav1.Status); ok {
+					return fmt.Errorf("ConfigCheck Pod watch for %s errored: %s", pod.Name, status.Message)
+				}
+				return fmt.Errorf("ConfigCheck Pod watch for %s errored", pod.Name)
+			}
+		}
+	}
+}
+
+// evaluateCheckPodPhase reports whether pod has reached a terminal phase and, if so,
+// the result of the check: nil on success, a *ValidationError on failure.
+func (cc *ConfigCheck) evaluateCheckPodPhase(ctx context.Context, pod *corev1.Pod, log logr.Logger) (bool, error) {
+	switch pod.Status.Phase {
+	case corev1.PodFailed:
+		reason, err := k8s.GetPodLogs(ctx, pod, cc.ClientSet)
 		if err != nil {
-			return err
+			return true, err
 		}
+		return true, newValidationError(reason)
+	case corev1.PodSucceeded:
+		log.Info("Config Check completed successfully")
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// deleteInFlight removes the validation Pod and its config Secret when the check is
+// abandoned before reaching a terminal phase (timeout or caller cancellation).
+func (cc *ConfigCheck) deleteInFlight(ctx context.Context, pod *corev1.Pod) error {
+	existing, err := k8s.GetPod(ctx, client.ObjectKeyFromObject(pod), cc.Client)
+	if err != nil {
+		return err
+	}
 
-		switch existing.Status.Phase {
-		case "Pending":
-			log.Info("wait Validate Vector Config Result")
-			time.Sleep(5 * time.Second)
-		case "Failed":
-			reason, err := k8s.GetPodLogs(ctx, pod, cc.ClientSet)
+	for _, v := range existing.Spec.Volumes {
+		if v.Name == "config" && v.Secret != nil {
+			secret, err := k8s.GetSecret(ctx, types.NamespacedName{Name: v.Secret.SecretName, Namespace: existing.Namespace}, cc.Client)
 			if err != nil {
 				return err
 			}
-			return newValidationError(reason)
-		case "Succeeded":
-			log.Info("Config Check completed successfully")
-			return nil
+			if err := k8s.DeleteSecret(ctx, secret, cc.Client); err != nil {
+				return err
+			}
 		}
 	}
+
+	return k8s.DeletePod(ctx, existing, cc.Client)
 }
 
 func (cc *ConfigCheck) cleanup(ctx context.Context) error {
@@ -185,6 +414,9 @@ func (cc *ConfigCheck) cleanup(ctx context.Context) error {
 	}
 	for _, pod := range podlist.Items {
 		if pod.Status.Phase == "Succeeded" {
+			if cc.CacheTTL == 0 || cc.withinCacheTTL(&pod) {
+				continue
+			}
 			for _, v := range pod.Spec.Volumes {
 				if v.Name == "config" {
 					nn := types.NamespacedName{
@@ -209,7 +441,7 @@ func (cc *ConfigCheck) cleanup(ctx context.Context) error {
 }
 
 func (cc *ConfigCheck) gcRListOptions() (client.ListOptions, error) {
-	configCheckLabels := labelsForVectorConfigCheck()
+	configCheckLabels := LabelsForVectorConfigCheck()
 	var requirements []labels.Requirement
 	for k, v := range configCheckLabels {
 		r, err := labels.NewRequirement(k, "==", []string{v})
@@ -0,0 +1,123 @@
+package configcheck
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestComputeHash(t *testing.T) {
+	base := &ConfigCheck{
+		Config: []byte("sources: {}"),
+		Image:  "timberio/vector:0.34.0-debian",
+		Envs: []corev1.EnvVar{
+			{Name: "B", Value: "2"},
+			{Name: "A", Value: "1"},
+		},
+	}
+
+	reordered := &ConfigCheck{
+		Config: base.Config,
+		Image:  base.Image,
+		Envs: []corev1.EnvVar{
+			{Name: "A", Value: "1"},
+			{Name: "B", Value: "2"},
+		},
+	}
+
+	diffConfig := &ConfigCheck{
+		Config: []byte("sources: {foo: {}}"),
+		Image:  base.Image,
+		Envs:   base.Envs,
+	}
+
+	diffImage := &ConfigCheck{
+		Config: base.Config,
+		Image:  "timberio/vector:0.35.0-debian",
+		Envs:   base.Envs,
+	}
+
+	if got, want := base.computeHash(), reordered.computeHash(); got != want {
+		t.Errorf("computeHash() must not depend on env ordering: got %q, want %q", got, want)
+	}
+	if got := base.computeHash(); len(got) != hashRunesLen {
+		t.Errorf("computeHash() length = %d, want %d", len(got), hashRunesLen)
+	}
+	if got, other := base.computeHash(), diffConfig.computeHash(); got == other {
+		t.Errorf("computeHash() did not change with Config: both %q", got)
+	}
+	if got, other := base.computeHash(), diffImage.computeHash(); got == other {
+		t.Errorf("computeHash() did not change with Image: both %q", got)
+	}
+}
+
+func TestLastGoodHash(t *testing.T) {
+	obj := &corev1.Secret{}
+
+	if _, ok := LastGoodHash(obj); ok {
+		t.Fatalf("LastGoodHash() on object with no annotations: got ok=true")
+	}
+
+	SetLastGoodHash(obj, "abcdef0123")
+
+	got, ok := LastGoodHash(obj)
+	if !ok {
+		t.Fatalf("LastGoodHash() after SetLastGoodHash: got ok=false")
+	}
+	if got != "abcdef0123" {
+		t.Errorf("LastGoodHash() = %q, want %q", got, "abcdef0123")
+	}
+
+	SetLastGoodHash(obj, "9876543210")
+	if got, _ := LastGoodHash(obj); got != "9876543210" {
+		t.Errorf("SetLastGoodHash() did not overwrite: got %q", got)
+	}
+}
+
+func TestSetLastGoodHashNilAnnotations(t *testing.T) {
+	obj := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: nil}}
+
+	SetLastGoodHash(obj, "deadbeef00")
+
+	if obj.Annotations == nil {
+		t.Fatal("SetLastGoodHash() left Annotations nil")
+	}
+	if obj.Annotations[LastGoodHashAnnotation] != "deadbeef00" {
+		t.Errorf("SetLastGoodHash() annotations = %v", obj.Annotations)
+	}
+}
+
+func TestEvaluateCheckPodPhase(t *testing.T) {
+	cc := &ConfigCheck{}
+	log := logr.Discard()
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		phase    corev1.PodPhase
+		wantDone bool
+		wantErr  bool
+	}{
+		{name: "succeeded is terminal with no error", phase: corev1.PodSucceeded, wantDone: true, wantErr: false},
+		{name: "pending is not terminal", phase: corev1.PodPending, wantDone: false, wantErr: false},
+		{name: "running is not terminal", phase: corev1.PodRunning, wantDone: false, wantErr: false},
+		{name: "unknown is not terminal", phase: corev1.PodUnknown, wantDone: false, wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{Status: corev1.PodStatus{Phase: tt.phase}}
+
+			done, err := cc.evaluateCheckPodPhase(ctx, pod, log)
+			if done != tt.wantDone {
+				t.Errorf("evaluateCheckPodPhase() done = %v, want %v", done, tt.wantDone)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("evaluateCheckPodPhase() err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package configcheck
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TimeoutError is returned when a ConfigCheck does not reach a terminal Pod phase
+// within its configured timeout. It is distinct from ValidationError so callers can
+// back off and retry instead of treating the config itself as invalid.
+type TimeoutError struct {
+	timeout time.Duration
+}
+
+func newTimeoutError(timeout time.Duration) *TimeoutError {
+	return &TimeoutError{timeout: timeout}
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("vector configcheck: timed out after %s waiting for validation Pod", e.timeout)
+}
+
+// IsTimeout reports whether err is (or wraps) a *TimeoutError, so callers of Run
+// can requeue instead of treating the config as invalid.
+func IsTimeout(err error) bool {
+	var timeoutErr *TimeoutError
+	return errors.As(err, &timeoutErr)
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configcheck
+
+import (
+	"context"
+
+	"github.com/kaasops/vector-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// EnsureVectorConfigCheck creates or updates the VectorConfigCheck CR requesting
+// validation of spec.ConfigSecretRef, owned by owner, so ConfigCheckReconciler picks
+// it up without anything blocking on the result.
+//
+// This is the producer half of the non-blocking design: no caller in this tree
+// invokes it yet. The Vector/VectorPipeline/VectorAggregator reconcilers the
+// originating request describes migrating onto create-or-update-a-VectorConfigCheck
+// aren't present in this source tree, so ConfigCheckReconciler itself still has no
+// real producer. Whoever adds those reconcilers should call this rather than
+// constructing a VectorConfigCheck by hand.
+func EnsureVectorConfigCheck(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner client.Object, name, namespace string, spec v1alpha1.VectorConfigCheckSpec) (*v1alpha1.VectorConfigCheck, error) {
+	check := &v1alpha1.VectorConfigCheck{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, c, check, func() error {
+		check.Spec = spec
+		return controllerutil.SetControllerReference(owner, check, scheme)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return check, nil
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kaasops/vector-operator/api/v1alpha1"
+	"github.com/kaasops/vector-operator/controllers/factory/config/configcheck"
+	"github.com/kaasops/vector-operator/controllers/factory/utils/k8s"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// ConfigCheckReconciler owns the Pod/Secret/ServiceAccount lifecycle of a single
+// VectorConfigCheck and writes the validation result back to its status, so
+// requesting reconcilers (Vector/VectorPipeline/VectorAggregator) never block a
+// worker on the validation Pod themselves and HA replicas dedupe on the CR name
+// instead of racing to create the same Pod.
+type ConfigCheckReconciler struct {
+	client.Client
+	// PodsClient reads/writes the validation Pod and its Secret through the
+	// dedicated ConfigCheck cache instead of the manager's main cache. The Pod
+	// watch in SetupWithManager still goes through the main cache.
+	PodsClient client.Client
+	Clientset  *kubernetes.Clientset
+	// CacheTTL is threaded into every configcheck.New call (see --configcheck-cache-ttl
+	// in cmd/manager/main.go); zero means a Succeeded validation Pod is cached forever.
+	CacheTTL time.Duration
+}
+
+// +kubebuilder:rbac:groups=observability.kaasops.io,resources=vectorconfigchecks,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=observability.kaasops.io,resources=vectorconfigchecks/status,verbs=get;update;patch
+
+func (r *ConfigCheckReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	// named sub-logger so --vmodule / per-logger verbosity can target configcheck
+	// validation noise without drowning out the rest of the reconciler fleet.
+	reqLog := log.FromContext(ctx).WithName("configcheck").WithValues("VectorConfigCheck", req.NamespacedName)
+	ctx = log.IntoContext(ctx, reqLog)
+
+	check := &v1alpha1.VectorConfigCheck{}
+	if err := r.Get(ctx, req.NamespacedName, check); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if check.Status.Phase == v1alpha1.VectorConfigCheckPhaseSucceeded || check.Status.Phase == v1alpha1.VectorConfigCheckPhaseFailed {
+		if check.Status.ObservedGeneration == check.Generation {
+			return ctrl.Result{}, nil
+		}
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: check.Spec.ConfigSecretRef.Name, Namespace: check.Namespace}, secret); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	cc := configcheck.New(
+		secret.Data["vector.yaml"],
+		r.PodsClient,
+		r.Clientset,
+		check.Name,
+		check.Namespace,
+		check.Spec.Image,
+		check.Spec.Envs,
+		time.Duration(check.Spec.TimeoutSeconds)*time.Second,
+		r.CacheTTL,
+	)
+
+	// A controller restart resets nothing on the CR itself, but if it raced a
+	// Pod deletion (or the Pod's cache window already lapsed) the annotation set
+	// below lets us skip straight to Succeeded instead of re-validating a config
+	// we already blessed.
+	if lastGood, ok := configcheck.LastGoodHash(check); ok && lastGood == cc.Hash {
+		check.Status.ObservedGeneration = check.Generation
+		check.Status.Hash = cc.Hash
+		check.Status.Phase = v1alpha1.VectorConfigCheckPhaseSucceeded
+		check.Status.Error = ""
+		if err := r.Status().Update(ctx, check); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// EnsurePod creates or reuses the validation Pod and returns immediately: it
+	// never blocks the worker waiting for a terminal phase. This reconcile observes
+	// whatever phase the Pod is currently in; SetupWithManager's Pod watch re-triggers
+	// Reconcile as that phase changes, so there's no busy-poll here either.
+	reqLog.Info("ensuring VectorConfigCheck Pod")
+	pod, err := cc.EnsurePod(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	check.Status.ObservedGeneration = check.Generation
+	check.Status.Hash = cc.Hash
+
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		check.Status.Phase = v1alpha1.VectorConfigCheckPhaseSucceeded
+		check.Status.Error = ""
+		if err := cc.Cleanup(ctx); err != nil {
+			return ctrl.Result{}, err
+		}
+		// Annotations live on ObjectMeta, not the status subresource, so this needs
+		// its own Update; the LastGoodHash check above is what a restart consults.
+		configcheck.SetLastGoodHash(check, cc.Hash)
+		// Update returns the stored object, whose status is the old one; keep ours.
+		status := check.Status
+		if err := r.Update(ctx, check); err != nil {
+			return ctrl.Result{}, err
+		}
+		check.Status = status
+	case corev1.PodFailed:
+		reason, err := k8s.GetPodLogs(ctx, pod, r.Clientset)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		check.Status.Phase = v1alpha1.VectorConfigCheckPhaseFailed
+		check.Status.Error = reason
+	default:
+		if !pod.CreationTimestamp.IsZero() && time.Since(pod.CreationTimestamp.Time) > cc.Timeout {
+			reqLog.Info("VectorConfigCheck Pod timed out waiting for a terminal phase, discarding it for a retry", "pod", pod.Name)
+			if err := cc.Abandon(ctx, pod); err != nil {
+				return ctrl.Result{}, err
+			}
+			check.Status.Phase = v1alpha1.VectorConfigCheckPhasePending
+			check.Status.Error = fmt.Sprintf("vector configcheck: timed out after %s waiting for validation Pod", cc.Timeout)
+			if err := r.Status().Update(ctx, check); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{Requeue: true}, nil
+		}
+		check.Status.Phase = v1alpha1.VectorConfigCheckPhasePending
+		check.Status.Error = ""
+		if err := r.Status().Update(ctx, check); err != nil {
+			return ctrl.Result{}, err
+		}
+		// A Pod stuck Pending/Running with no further events (e.g. unschedulable)
+		// never emits another watch event, so the timeout check above needs its
+		// own requeue to be guaranteed to run at all.
+		remaining := cc.Timeout - time.Since(pod.CreationTimestamp.Time)
+		if remaining < 0 {
+			remaining = 0
+		}
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+
+	if err := r.Status().Update(ctx, check); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// mapCheckPodToRequest maps a validation Pod event back to the VectorConfigCheck
+// that requested it, via configcheck.OwnerNameLabel.
+func mapCheckPodToRequest(_ context.Context, obj client.Object) []ctrl.Request {
+	name, ok := obj.GetLabels()[configcheck.OwnerNameLabel]
+	if !ok {
+		return nil
+	}
+	return []ctrl.Request{{NamespacedName: types.NamespacedName{Name: name, Namespace: obj.GetNamespace()}}}
+}
+
+// SetupWithManager registers the reconciler. The Pod watch below is what lets
+// Reconcile progress a VectorConfigCheck's status off Pod events instead of
+// blocking a worker on cc.Run's watch loop (see configcheck.EnsurePod).
+func (r *ConfigCheckReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.VectorConfigCheck{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&corev1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(mapCheckPodToRequest),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Named("vectorconfigcheck").
+		Complete(r)
+}